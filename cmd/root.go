@@ -0,0 +1,38 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/srl-labs/containerlab/cmd/version"
+)
+
+// rootCmd represents the base command when called without any subcommands.
+var rootCmd = &cobra.Command{
+	Use:   "containerlab",
+	Short: "deploy container based lab environments with a user-defined interconnections",
+	PersistentPostRun: func(_ *cobra.Command, _ []string) {
+		version.RunBackgroundUpdateCheck(version.Version)
+	},
+}
+
+// Execute adds all child commands to the root command and sets flags appropriately.
+// This is called by main.main(). It only needs to happen once to the rootCmd.
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(version.VersionCmd)
+
+	rootCmd.PersistentFlags().BoolVar(&version.NoUpdateCheck, "no-update-check", false,
+		"disable the periodic background check for new containerlab releases")
+}
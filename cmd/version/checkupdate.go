@@ -0,0 +1,76 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package version
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/srl-labs/containerlab/internal/updatecheck"
+)
+
+// NoUpdateCheck disables the periodic background update check. It is bound to
+// the --no-update-check persistent flag on the root command.
+var NoUpdateCheck bool
+
+// RunBackgroundUpdateCheck prints a notice when a release newer than
+// currentTag is already cached, then refreshes the cache from GitHub in a
+// detached goroutine if it is missing, older than updatecheck.Interval or was
+// written for a different running version.
+// It never blocks the calling command and is safe to call unconditionally
+// from every command invocation: the goroutine carries its own 3s timeout
+// and simply dies with the process if the command finishes first.
+func RunBackgroundUpdateCheck(currentTag string) {
+	if !shouldCheckForUpdates() {
+		return
+	}
+
+	if cached, err := updatecheck.ReadCache(); err == nil && cached != nil {
+		printNoticeIfNewer(cached.LatestTag, currentTag)
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+
+		_, _ = updatecheck.Check(ctx, currentTag)
+	}()
+}
+
+// shouldCheckForUpdates reports whether the periodic update check should run
+// at all, honoring the --no-update-check flag, the CLAB_DISABLE_UPDATE_CHECK
+// env var, non-interactive stdout and CI environments.
+func shouldCheckForUpdates() bool {
+	if NoUpdateCheck {
+		return false
+	}
+
+	if os.Getenv("CLAB_DISABLE_UPDATE_CHECK") == "1" {
+		return false
+	}
+
+	if os.Getenv("CI") == "true" {
+		return false
+	}
+
+	fi, err := os.Stdout.Stat()
+	if err != nil || (fi.Mode()&os.ModeCharDevice) == 0 {
+		return false
+	}
+
+	return true
+}
+
+// printNoticeIfNewer prints a one-line upgrade notice when latestTag is newer
+// than currentTag.
+func printNoticeIfNewer(latestTag, currentTag string) {
+	if !updatecheck.IsNewer(latestTag, currentTag) {
+		return
+	}
+
+	fmt.Printf("\na new version %s is available, run `containerlab version upgrade` to upgrade\n", latestTag)
+}
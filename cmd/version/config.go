@@ -0,0 +1,54 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package version
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// releaseConfig is the contents of the release config file that `version upgrade`
+// reads to let operators pin a fleet of hosts to a release channel, filter or exact
+// version without passing flags on every invocation. There is no other containerlab
+// config file this hooks into today; it is a dedicated file introduced for this
+// feature, and is the lowest-priority source behind flags and CLAB_RELEASE_* env vars.
+type releaseConfig struct {
+	ReleaseChannel string `json:"release_channel,omitempty"`
+	ReleaseFilter  string `json:"release_filter,omitempty"`
+	ReleasePin     string `json:"release_pin,omitempty"`
+}
+
+// configPath returns the path to the release config file,
+// ~/.config/containerlab/release.json.
+func configPath() (string, error) {
+	cfgDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cfgDir, "containerlab", "release.json"), nil
+}
+
+// loadReleaseConfig reads the release config file. A missing or unreadable file is not
+// an error: it just means no keys are set.
+func loadReleaseConfig() releaseConfig {
+	path, err := configPath()
+	if err != nil {
+		return releaseConfig{}
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return releaseConfig{}
+	}
+
+	var cfg releaseConfig
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return releaseConfig{}
+	}
+
+	return cfg
+}
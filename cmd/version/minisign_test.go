@@ -0,0 +1,161 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package version
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildMinisignKeyPair returns a base64 minisign public key blob and the matching
+// private key, both tagged with keyID.
+func buildMinisignKeyPair(t *testing.T, keyID [8]byte) (string, ed25519.PrivateKey) {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("failed to generate ed25519 key: %v", err)
+	}
+
+	blob := append([]byte{}, minisignAlgEd25519[:]...)
+	blob = append(blob, keyID[:]...)
+	blob = append(blob, pub...)
+
+	return base64.StdEncoding.EncodeToString(blob), priv
+}
+
+// signMinisign signs data with priv and returns a minisign .sig file body, the way
+// `minisign -S` would produce one.
+func signMinisign(priv ed25519.PrivateKey, keyID [8]byte, data []byte, trustedComment string) []byte {
+	sig := ed25519.Sign(priv, data)
+
+	sigBlob := append([]byte{}, minisignAlgEd25519[:]...)
+	sigBlob = append(sigBlob, keyID[:]...)
+	sigBlob = append(sigBlob, sig...)
+
+	globalSig := ed25519.Sign(priv, append(append([]byte{}, sig...), []byte(trustedComment)...))
+
+	out := "untrusted comment: signature from minisign secret key\n"
+	out += base64.StdEncoding.EncodeToString(sigBlob) + "\n"
+	out += "trusted comment: " + trustedComment + "\n"
+	out += base64.StdEncoding.EncodeToString(globalSig) + "\n"
+
+	return []byte(out)
+}
+
+func TestParseMinisignPublicKey(t *testing.T) {
+	keyID := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+	b64, priv := buildMinisignKeyPair(t, keyID)
+
+	pk, err := parseMinisignPublicKey(b64)
+	if err != nil {
+		t.Fatalf("parseMinisignPublicKey() error = %v", err)
+	}
+	if pk.keyID != keyID {
+		t.Errorf("parseMinisignPublicKey().keyID = %v, want %v", pk.keyID, keyID)
+	}
+	if !pk.key.Equal(priv.Public().(ed25519.PublicKey)) {
+		t.Errorf("parseMinisignPublicKey().key does not match the generated public key")
+	}
+
+	if _, err := parseMinisignPublicKey("not-base64!!!"); err == nil {
+		t.Error("parseMinisignPublicKey() with invalid base64 = nil error, want an error")
+	}
+	if _, err := parseMinisignPublicKey(base64.StdEncoding.EncodeToString([]byte("too short"))); err == nil {
+		t.Error("parseMinisignPublicKey() with wrong-length key = nil error, want an error")
+	}
+}
+
+func TestParseMinisignSignature(t *testing.T) {
+	keyID := [8]byte{9, 9, 9, 9, 9, 9, 9, 9}
+	_, priv := buildMinisignKeyPair(t, keyID)
+	data := []byte("release tarball contents")
+
+	body := signMinisign(priv, keyID, data, "timestamp:1700000000\tfile:containerlab.tar.gz")
+
+	sig, err := parseMinisignSignature(body)
+	if err != nil {
+		t.Fatalf("parseMinisignSignature() error = %v", err)
+	}
+	if sig.keyID != keyID {
+		t.Errorf("parseMinisignSignature().keyID = %v, want %v", sig.keyID, keyID)
+	}
+	if sig.trustedComment != "timestamp:1700000000\tfile:containerlab.tar.gz" {
+		t.Errorf("parseMinisignSignature().trustedComment = %q", sig.trustedComment)
+	}
+	if !ed25519.Verify(priv.Public().(ed25519.PublicKey), data, sig.sig) {
+		t.Error("parseMinisignSignature().sig does not verify over the signed data")
+	}
+
+	if _, err := parseMinisignSignature([]byte("too\nshort\n")); err == nil {
+		t.Error("parseMinisignSignature() with too few lines = nil error, want an error")
+	}
+}
+
+func TestVerifySignature(t *testing.T) {
+	keyID := [8]byte{4, 4, 4, 4, 4, 4, 4, 4}
+	pubB64, priv := buildMinisignKeyPair(t, keyID)
+
+	data := []byte("a fake release tarball")
+	tmpFile := filepath.Join(t.TempDir(), "containerlab.tar.gz")
+	if err := os.WriteFile(tmpFile, data, 0o644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	t.Run("valid signature verifies", func(t *testing.T) {
+		sigBody := signMinisign(priv, keyID, data, "file:containerlab.tar.gz")
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(sigBody)
+		}))
+		defer srv.Close()
+
+		if err := verifySignatureWithKey(context.Background(), tmpFile, srv.URL, pubB64); err != nil {
+			t.Errorf("verifySignatureWithKey() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("tampered file fails verification", func(t *testing.T) {
+		sigBody := signMinisign(priv, keyID, data, "file:containerlab.tar.gz")
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(sigBody)
+		}))
+		defer srv.Close()
+
+		tamperedFile := filepath.Join(t.TempDir(), "containerlab.tar.gz")
+		if err := os.WriteFile(tamperedFile, append(data, '!'), 0o644); err != nil {
+			t.Fatalf("failed to write tampered file: %v", err)
+		}
+
+		if err := verifySignatureWithKey(context.Background(), tamperedFile, srv.URL, pubB64); err == nil {
+			t.Error("verifySignatureWithKey() over a tampered file = nil error, want an error")
+		}
+	})
+
+	t.Run("signature from a different key is rejected", func(t *testing.T) {
+		otherKeyID := [8]byte{5, 5, 5, 5, 5, 5, 5, 5}
+		_, otherPriv := buildMinisignKeyPair(t, otherKeyID)
+		sigBody := signMinisign(otherPriv, otherKeyID, data, "file:containerlab.tar.gz")
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(sigBody)
+		}))
+		defer srv.Close()
+
+		if err := verifySignatureWithKey(context.Background(), tmpFile, srv.URL, pubB64); err == nil {
+			t.Error("verifySignatureWithKey() with a mismatched key ID = nil error, want an error")
+		}
+	})
+
+	t.Run("no bundled key is a no-op", func(t *testing.T) {
+		if err := verifySignatureWithKey(context.Background(), tmpFile, "http://example.invalid/should-not-be-fetched", ""); err != nil {
+			t.Errorf("verifySignatureWithKey() with no bundled key error = %v, want nil", err)
+		}
+	})
+}
@@ -0,0 +1,514 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package version
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+const (
+	defaultRepo = "srl-labs/containerlab"
+
+	envReleaseSource  = "CLAB_RELEASE_SOURCE"
+	envReleaseBaseURL = "CLAB_RELEASE_BASE_URL"
+	envReleaseRepo    = "CLAB_RELEASE_REPO"
+)
+
+var (
+	releaseSourceFlag  string
+	releaseBaseURLFlag string
+	releaseRepoFlag    string
+)
+
+// ReleaseSource abstracts where `version upgrade` resolves tags and downloads release
+// tarballs from, so enterprise users mirroring containerlab internally (or forking it
+// on GitLab) don't need to patch this command to use it.
+type ReleaseSource interface {
+	// LatestTag resolves the newest tag available from this source that satisfies policy.
+	LatestTag(ctx context.Context, policy tagPolicy) (string, error)
+	// FetchInstaller returns a reader over the release tarball matching the running
+	// OS/arch for tag. The caller must close it.
+	FetchInstaller(ctx context.Context, tag string) (io.ReadCloser, error)
+}
+
+// resolveReleaseSource picks a ReleaseSource based on the upgrade command's flags,
+// falling back to CLAB_RELEASE_SOURCE/CLAB_RELEASE_BASE_URL/CLAB_RELEASE_REPO.
+func resolveReleaseSource() (ReleaseSource, error) {
+	src := releaseSourceFlag
+	if src == "" {
+		src = os.Getenv(envReleaseSource)
+	}
+	if src == "" {
+		src = "github"
+	}
+
+	baseURL := releaseBaseURLFlag
+	if baseURL == "" {
+		baseURL = os.Getenv(envReleaseBaseURL)
+	}
+
+	repo := releaseRepoFlag
+	if repo == "" {
+		repo = os.Getenv(envReleaseRepo)
+	}
+	if repo == "" {
+		repo = defaultRepo
+	}
+
+	switch strings.ToLower(src) {
+	case "github":
+		return &GitHubSource{Repo: repo}, nil
+	case "gitlab":
+		if baseURL == "" {
+			baseURL = "https://gitlab.com"
+		}
+		return &GitLabSource{BaseURL: baseURL, Repo: repo}, nil
+	case "mirror":
+		if baseURL == "" {
+			return nil, fmt.Errorf("--release-base-url (or %s) is required when --release-source=mirror", envReleaseBaseURL)
+		}
+		return &HTTPMirrorSource{BaseURL: baseURL}, nil
+	default:
+		return nil, fmt.Errorf("unknown release source %q: must be one of github, gitlab, mirror", src)
+	}
+}
+
+// releaseSourceName returns a human-readable name for src, for error messages.
+func releaseSourceName(src ReleaseSource) string {
+	switch src.(type) {
+	case *GitHubSource:
+		return "github"
+	case *GitLabSource:
+		return "gitlab"
+	case *HTTPMirrorSource:
+		return "mirror"
+	default:
+		return fmt.Sprintf("%T", src)
+	}
+}
+
+// GitHubSource resolves tags and release assets against the GitHub Releases API. It is
+// the default source and preserves the behavior `version upgrade` has always had.
+type GitHubSource struct {
+	Repo string
+}
+
+func (s *GitHubSource) repo() string {
+	if s.Repo != "" {
+		return s.Repo
+	}
+	return defaultRepo
+}
+
+// LatestTag implements ReleaseSource.
+func (s *GitHubSource) LatestTag(ctx context.Context, policy tagPolicy) (string, error) {
+	if policy.pin != "" {
+		return normalizeTag(policy.pin), nil
+	}
+
+	url := fmt.Sprintf("https://api.github.com/repos/%s/tags", s.repo())
+
+	var latestVersion *version.Version
+	var latestTag string
+
+	for page := 1; page <= maxTagPages; page++ {
+		tags, err := fetchTagPage(ctx, url, page)
+		if err != nil {
+			return "", err
+		}
+		if len(tags) == 0 {
+			break
+		}
+
+		for _, t := range tags {
+			if !isVersionTag(t.Name) || !policy.matches(t.Name) {
+				continue
+			}
+
+			v, err := version.NewVersion(strings.TrimPrefix(t.Name, "v"))
+			if err != nil {
+				continue
+			}
+			if latestVersion == nil || v.GreaterThan(latestVersion) {
+				latestVersion = v
+				latestTag = t.Name
+			}
+		}
+
+		if len(tags) < tagsPerPage {
+			break
+		}
+	}
+
+	if latestTag == "" {
+		return "", fmt.Errorf("no tag matches the configured release channel/filter")
+	}
+
+	return latestTag, nil
+}
+
+// FetchInstaller implements ReleaseSource. It downloads the release tarball for tag,
+// verifies its checksum (and an optional detached signature) and returns a reader over
+// the verified file; the backing temp directory is removed when the reader is closed.
+func (s *GitHubSource) FetchInstaller(ctx context.Context, tag string) (io.ReadCloser, error) {
+	rel, err := getGitHubRelease(ctx, s.repo(), tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release %s: %w", tag, err)
+	}
+
+	assetName, err := releaseAssetName(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	asset := findAsset(rel.Assets, assetName)
+	if asset == nil {
+		return nil, fmt.Errorf("release %s has no asset named %s for this platform", tag, assetName)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "containerlab-release")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+
+	tarPath := tmpDir + string(os.PathSeparator) + assetName
+	if err := downloadToPath(ctx, asset.BrowserDownloadURL, tarPath); err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, fmt.Errorf("failed to download %s: %w", assetName, err)
+	}
+
+	if checksums := findAsset(rel.Assets, "checksums.txt"); checksums != nil {
+		if err := verifyChecksum(ctx, tarPath, assetName, checksums.BrowserDownloadURL); err != nil {
+			os.RemoveAll(tmpDir)
+			return nil, err
+		}
+	} else {
+		fmt.Println("warning: release has no checksums.txt asset, skipping checksum verification")
+	}
+
+	if sig := findAsset(rel.Assets, assetName+".sig"); sig != nil {
+		if err := verifySignature(ctx, tarPath, sig.BrowserDownloadURL); err != nil {
+			os.RemoveAll(tmpDir)
+			return nil, err
+		}
+	}
+
+	f, err := os.Open(tarPath)
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return nil, err
+	}
+
+	return &tempDirFile{File: f, dir: tmpDir}, nil
+}
+
+// GitLabSource resolves tags and release assets against a GitLab (gitlab.com or
+// self-hosted) releases API.
+type GitLabSource struct {
+	BaseURL string
+	Repo    string
+}
+
+type gitlabRelease struct {
+	TagName string `json:"tag_name"`
+	Assets  struct {
+		Links []struct {
+			Name           string `json:"name"`
+			DirectAssetURL string `json:"direct_asset_url"`
+		} `json:"links"`
+	} `json:"assets"`
+}
+
+func (s *GitLabSource) projectPath() string {
+	return fmt.Sprintf("%s/api/v4/projects/%s/releases", strings.TrimSuffix(s.BaseURL, "/"), url.PathEscape(s.Repo))
+}
+
+// LatestTag implements ReleaseSource.
+func (s *GitLabSource) LatestTag(ctx context.Context, policy tagPolicy) (string, error) {
+	if policy.pin != "" {
+		return normalizeTag(policy.pin), nil
+	}
+
+	releases, err := s.fetchReleases(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var latestVersion *version.Version
+	var latestTag string
+	for _, rel := range releases {
+		if !isVersionTag(rel.TagName) || !policy.matches(rel.TagName) {
+			continue
+		}
+
+		v, err := version.NewVersion(strings.TrimPrefix(rel.TagName, "v"))
+		if err != nil {
+			continue
+		}
+		if latestVersion == nil || v.GreaterThan(latestVersion) {
+			latestVersion = v
+			latestTag = rel.TagName
+		}
+	}
+
+	if latestTag == "" {
+		return "", fmt.Errorf("no tag matches the configured release channel/filter")
+	}
+
+	return latestTag, nil
+}
+
+// FetchInstaller implements ReleaseSource. It does not verify a checksum or signature:
+// GitLab release assets carry no standardized equivalent of GitHub's checksums.txt, so
+// operators relying on this source are trusting the project/GitLab instance itself.
+func (s *GitLabSource) FetchInstaller(ctx context.Context, tag string) (io.ReadCloser, error) {
+	fmt.Println("warning: GitLab release source has no checksum verification, installing unverified")
+
+	assetName, err := releaseAssetName(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	releases, err := s.fetchReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rel := range releases {
+		if rel.TagName != tag {
+			continue
+		}
+
+		for _, link := range rel.Assets.Links {
+			if link.Name == assetName {
+				req, err := http.NewRequestWithContext(ctx, http.MethodGet, link.DirectAssetURL, nil)
+				if err != nil {
+					return nil, err
+				}
+
+				resp, err := http.DefaultClient.Do(req)
+				if err != nil {
+					return nil, err
+				}
+				if resp.StatusCode != http.StatusOK {
+					resp.Body.Close()
+					return nil, fmt.Errorf("unexpected status %s fetching %s", resp.Status, assetName)
+				}
+
+				return resp.Body, nil
+			}
+		}
+
+		return nil, fmt.Errorf("release %s has no asset named %s for this platform", tag, assetName)
+	}
+
+	return nil, fmt.Errorf("release %s not found", tag)
+}
+
+func (s *GitLabSource) fetchReleases(ctx context.Context) ([]gitlabRelease, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.projectPath(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s fetching GitLab releases", resp.Status)
+	}
+
+	var releases []gitlabRelease
+	if err := json.NewDecoder(resp.Body).Decode(&releases); err != nil {
+		return nil, err
+	}
+
+	return releases, nil
+}
+
+// HTTPMirrorSource serves release tarballs from a plain directory listing, for
+// air-gapped labs that mirror containerlab releases internally.
+type HTTPMirrorSource struct {
+	BaseURL string
+}
+
+var mirrorTagRe = regexp.MustCompile(`containerlab_([0-9][^_]*)_linux_`)
+
+// LatestTag implements ReleaseSource by parsing an HTML directory listing at BaseURL for
+// release tarball names and picking the highest version that satisfies policy.
+func (s *HTTPMirrorSource) LatestTag(ctx context.Context, policy tagPolicy) (string, error) {
+	if policy.pin != "" {
+		return normalizeTag(policy.pin), nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(s.BaseURL, "/")+"/", nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var latestVersion *version.Version
+	var latestTag string
+	for _, m := range mirrorTagRe.FindAllStringSubmatch(string(body), -1) {
+		tag := "v" + m[1]
+		if !policy.matches(tag) {
+			continue
+		}
+
+		v, err := version.NewVersion(m[1])
+		if err != nil {
+			continue
+		}
+		if latestVersion == nil || v.GreaterThan(latestVersion) {
+			latestVersion = v
+			latestTag = tag
+		}
+	}
+
+	if latestTag == "" {
+		return "", fmt.Errorf("no release tarball matching the configured release channel/filter found at %s", s.BaseURL)
+	}
+
+	return latestTag, nil
+}
+
+// FetchInstaller implements ReleaseSource by downloading <BaseURL>/<assetName> directly.
+// It does not verify a checksum: operators pointing at a mirror are trusting whatever
+// serves that directory listing (presumably their own infrastructure).
+func (s *HTTPMirrorSource) FetchInstaller(ctx context.Context, tag string) (io.ReadCloser, error) {
+	fmt.Println("warning: mirror release source has no checksum verification, installing unverified")
+
+	assetName, err := releaseAssetName(tag)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		strings.TrimSuffix(s.BaseURL, "/")+"/"+assetName, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %s fetching %s from mirror", resp.Status, assetName)
+	}
+
+	return resp.Body, nil
+}
+
+// tempDirFile wraps an *os.File whose parent directory should be removed once the file
+// is closed.
+type tempDirFile struct {
+	*os.File
+	dir string
+}
+
+func (f *tempDirFile) Close() error {
+	err := f.File.Close()
+	os.RemoveAll(f.dir)
+
+	return err
+}
+
+// isVersionTag reports whether name looks like a "vX.Y.Z"-style version tag.
+func isVersionTag(name string) bool {
+	return strings.HasPrefix(name, "v") && len(name) >= 2 && name[1] >= '0' && name[1] <= '9'
+}
+
+// normalizeTag ensures tag has a leading "v", as release tags do.
+func normalizeTag(tag string) string {
+	if strings.HasPrefix(tag, "v") {
+		return tag
+	}
+	return "v" + tag
+}
+
+// fetchTagPage retrieves a single page of tagsURL.
+func fetchTagPage(ctx context.Context, tagsURL string, page int) ([]tagInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("%s?per_page=%d&page=%d", tagsURL, tagsPerPage, page), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s fetching tags page %d", resp.Status, page)
+	}
+
+	var tags []tagInfo
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func getGitHubRelease(ctx context.Context, repo, tag string) (*releaseInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		fmt.Sprintf("https://api.github.com/repos/%s/releases/tags/%s", repo, tag), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s fetching release %s", resp.Status, tag)
+	}
+
+	var rel releaseInfo
+	if err := json.NewDecoder(resp.Body).Decode(&rel); err != nil {
+		return nil, err
+	}
+
+	return &rel, nil
+}
+
+func init() {
+	upgradeCmd.Flags().StringVar(&releaseSourceFlag, "release-source", "",
+		"release source to resolve and install from: github|gitlab|mirror (default github)")
+	upgradeCmd.Flags().StringVar(&releaseBaseURLFlag, "release-base-url", "",
+		"base URL of the release source, required for --release-source=mirror and for self-hosted GitLab")
+	upgradeCmd.Flags().StringVar(&releaseRepoFlag, "release-repo", "",
+		"project/repo to fetch releases from (e.g. group/project on GitLab)")
+}
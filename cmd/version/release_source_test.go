@@ -0,0 +1,146 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package version
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestTagPolicyMatches(t *testing.T) {
+	tests := map[string]struct {
+		policy tagPolicy
+		tag    string
+		want   bool
+	}{
+		"stable channel accepts a stable tag":     {tagPolicy{channel: channelStable}, "v0.54.0", true},
+		"stable channel rejects an rc":            {tagPolicy{channel: channelStable}, "v0.54.0-rc1", false},
+		"stable channel rejects a beta":           {tagPolicy{channel: channelStable}, "v0.54.0-beta1", false},
+		"prerelease channel accepts an rc":        {tagPolicy{channel: channelPrerelease}, "v0.54.0-rc1", true},
+		"prerelease channel accepts a stable tag": {tagPolicy{channel: channelPrerelease}, "v0.54.0", true},
+		"filter overrides channel for a matching tag": {
+			tagPolicy{channel: channelStable, filter: regexp.MustCompile(`-rc`)}, "v0.54.0-rc1", true,
+		},
+		"filter overrides channel for a non-matching tag": {
+			tagPolicy{channel: channelPrerelease, filter: regexp.MustCompile(`^v0\.6`)}, "v0.54.0", false,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.policy.matches(tc.tag); got != tc.want {
+				t.Errorf("policy.matches(%q) = %v, want %v", tc.tag, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestIsVersionTag(t *testing.T) {
+	tests := map[string]struct {
+		name string
+		want bool
+	}{
+		"valid":            {"v0.54.0", true},
+		"valid prerelease": {"v0.54.0-rc1", true},
+		"no v prefix":      {"0.54.0", false},
+		"v alone":          {"v", false},
+		"not a version":    {"vfoo", false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := isVersionTag(tc.name); got != tc.want {
+				t.Errorf("isVersionTag(%q) = %v, want %v", tc.name, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeTag(t *testing.T) {
+	tests := map[string]struct {
+		in, want string
+	}{
+		"already has v prefix": {"v0.54.0", "v0.54.0"},
+		"missing v prefix":     {"0.54.0", "v0.54.0"},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := normalizeTag(tc.in); got != tc.want {
+				t.Errorf("normalizeTag(%q) = %q, want %q", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+// fakeReleaseSource is the in-memory ReleaseSource fake called for by the
+// pluggable-release-source request: it lets resolution/selection logic be exercised
+// without reaching any real GitHub/GitLab/mirror endpoint.
+type fakeReleaseSource struct {
+	tags      []string
+	installer string
+}
+
+func (f *fakeReleaseSource) LatestTag(_ context.Context, policy tagPolicy) (string, error) {
+	if policy.pin != "" {
+		return normalizeTag(policy.pin), nil
+	}
+
+	var latest string
+	for _, tag := range f.tags {
+		if policy.matches(tag) {
+			latest = tag
+		}
+	}
+
+	if latest == "" {
+		return "", errNoMatch
+	}
+
+	return latest, nil
+}
+
+func (f *fakeReleaseSource) FetchInstaller(_ context.Context, _ string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(f.installer)), nil
+}
+
+var errNoMatch = errNoMatchingTag{}
+
+type errNoMatchingTag struct{}
+
+func (errNoMatchingTag) Error() string { return "no tag matches the configured release channel/filter" }
+
+func TestFakeReleaseSourceImplementsInterface(t *testing.T) {
+	var _ ReleaseSource = (*fakeReleaseSource)(nil)
+
+	src := &fakeReleaseSource{tags: []string{"v0.53.0", "v0.54.0", "v0.55.0-rc1"}}
+
+	got, err := src.LatestTag(context.Background(), tagPolicy{channel: channelStable})
+	if err != nil {
+		t.Fatalf("LatestTag() error = %v", err)
+	}
+	if got != "v0.54.0" {
+		t.Errorf("LatestTag() = %q, want v0.54.0 (the rc should be filtered out by the stable channel)", got)
+	}
+
+	got, err = src.LatestTag(context.Background(), tagPolicy{channel: channelPrerelease})
+	if err != nil {
+		t.Fatalf("LatestTag() error = %v", err)
+	}
+	if got != "v0.55.0-rc1" {
+		t.Errorf("LatestTag() = %q, want v0.55.0-rc1 under the prerelease channel", got)
+	}
+
+	got, err = src.LatestTag(context.Background(), tagPolicy{pin: "0.50.0"})
+	if err != nil {
+		t.Fatalf("LatestTag() error = %v", err)
+	}
+	if got != "v0.50.0" {
+		t.Errorf("LatestTag() = %q, want the normalized pin v0.50.0", got)
+	}
+}
@@ -5,66 +5,184 @@
 package version
 
 import (
-	"encoding/json"
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
 	"strings"
 
-	"github.com/hashicorp/go-version"
 	"github.com/spf13/cobra"
 	"github.com/srl-labs/containerlab/cmd/common"
 )
 
 const (
 	downloadURL = "https://github.com/srl-labs/containerlab/raw/main/get.sh"
-	tagsURL     = "https://api.github.com/repos/srl-labs/containerlab/tags"
+
+	// releasePublicKeyB64 is the base64-encoded minisign public key (as printed by
+	// `minisign -G`, a 2-byte algorithm tag + 8-byte key ID + 32-byte ed25519 key) used
+	// to verify detached release signatures. It is left empty until a signing key is
+	// provisioned for release builds; signature verification is skipped (with a
+	// warning) while it is empty.
+	releasePublicKeyB64 = ""
+
+	// tagsPerPage is the page size requested from the /tags endpoint. GitHub caps
+	// it at 100 regardless of what's requested.
+	tagsPerPage = 100
+	// maxTagPages bounds how many pages GitHubSource.LatestTag will walk looking for a tag
+	// that matches the configured channel/filter, so a long run of prereleases
+	// can't turn a lookup into an unbounded crawl.
+	maxTagPages = 10
+)
+
+var (
+	legacyUpgrade bool
+	fromFile      string
+
+	releaseChannel string
+	releaseFilter  string
+	releasePin     string
+)
+
+// releaseChannel values recognized by --channel.
+const (
+	channelStable     = "stable"
+	channelPrerelease = "prerelease"
 )
 
+// prereleaseMarkers are the tag substrings that mark a tag as a prerelease
+// under the "stable" channel.
+var prereleaseMarkers = []string{"-rc", "-beta", "-alpha", "-dev"}
+
+// tagPolicy controls which tag ReleaseSource.LatestTag is allowed to return.
+type tagPolicy struct {
+	channel string
+	filter  *regexp.Regexp
+	pin     string
+}
+
+// matches reports whether tag is acceptable under p. A filter, when set,
+// takes precedence over the channel.
+func (p tagPolicy) matches(tag string) bool {
+	if p.filter != nil {
+		return p.filter.MatchString(tag)
+	}
+
+	if p.channel == channelPrerelease {
+		return true
+	}
+
+	for _, marker := range prereleaseMarkers {
+		if strings.Contains(tag, marker) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// resolvePolicy builds a tagPolicy from the upgrade command's flags, falling back in
+// order to CLAB_RELEASE_CHANNEL/CLAB_RELEASE_FILTER/CLAB_RELEASE_PIN and then to the
+// release_channel/release_filter/release_pin keys in the release config file when
+// a flag wasn't explicitly passed on the command line. This lets a fleet of hosts be
+// pinned to a channel or minor line via config or environment rather than editing every
+// invocation.
+func resolvePolicy(cmd *cobra.Command) (tagPolicy, error) {
+	cfg := loadReleaseConfig()
+
+	channel := releaseChannel
+	if !cmd.Flags().Changed("channel") {
+		switch {
+		case os.Getenv("CLAB_RELEASE_CHANNEL") != "":
+			channel = os.Getenv("CLAB_RELEASE_CHANNEL")
+		case cfg.ReleaseChannel != "":
+			channel = cfg.ReleaseChannel
+		}
+	}
+
+	filter := releaseFilter
+	if !cmd.Flags().Changed("filter") {
+		switch {
+		case os.Getenv("CLAB_RELEASE_FILTER") != "":
+			filter = os.Getenv("CLAB_RELEASE_FILTER")
+		case cfg.ReleaseFilter != "":
+			filter = cfg.ReleaseFilter
+		}
+	}
+
+	pin := releasePin
+	if !cmd.Flags().Changed("pin") {
+		switch {
+		case os.Getenv("CLAB_RELEASE_PIN") != "":
+			pin = os.Getenv("CLAB_RELEASE_PIN")
+		case cfg.ReleasePin != "":
+			pin = cfg.ReleasePin
+		}
+	}
+
+	policy := tagPolicy{channel: channel, pin: pin}
+
+	if filter != "" {
+		re, err := regexp.Compile(filter)
+		if err != nil {
+			return tagPolicy{}, fmt.Errorf("invalid --filter regex: %w", err)
+		}
+		policy.filter = re
+	}
+
+	return policy, nil
+}
+
 // upgradeCmd represents the upgrade command.
 var upgradeCmd = &cobra.Command{
 	Use:     "upgrade",
 	Short:   "upgrade containerlab to latest available version",
 	PreRunE: common.CheckAndGetRootPrivs,
-	RunE: func(_ *cobra.Command, args []string) error {
-		// Determine the latest version tag using GitHub's API.
-		latest, err := getLatestTag()
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		ctx := context.Background()
+
+		if fromFile != "" {
+			fmt.Printf("installing containerlab from local file %s\n", fromFile)
+			return selfUpdateFromFile(fromFile)
+		}
+
+		policy, err := resolvePolicy(cmd)
 		if err != nil {
-			return fmt.Errorf("failed to determine latest version: %w", err)
+			return err
 		}
-		fmt.Printf("aarafat-tag: getting the latest version using GitHub tags method.... %s\n", latest)
-		fmt.Printf("Latest tag: %s\n", latest)
 
-		// Create a temporary file to hold the upgrade script.
-		f, err := os.CreateTemp("", "containerlab")
+		src, err := resolveReleaseSource()
 		if err != nil {
-			return fmt.Errorf("failed to create temp file: %w", err)
+			return err
 		}
-		defer os.Remove(f.Name())
 
-		// Download the upgrade script into the temp file.
-		if err = downloadFile(downloadURL, f); err != nil {
-			return fmt.Errorf("failed to download upgrade script: %w", err)
+		if legacyUpgrade {
+			if _, ok := src.(*GitHubSource); !ok {
+				return fmt.Errorf("--legacy only supports the github release source, got %q", releaseSourceName(src))
+			}
 		}
 
-		// Ensure the file is executable.
-		if err = f.Chmod(0755); err != nil {
-			return fmt.Errorf("failed to set script as executable: %w", err)
+		latest, err := src.LatestTag(ctx, policy)
+		if err != nil {
+			return fmt.Errorf("failed to determine latest version: %w", err)
 		}
+		fmt.Printf("latest available version: %s\n", latest)
 
-		// Prepare and run the upgrade script.
-		// The environment variable CLAB_VERSION is passed so that the script installs the latest version.
-		c := exec.Command("sudo", "bash", f.Name())
-		c.Env = append(os.Environ(), "CLAB_VERSION="+latest)
-		c.Stdout = os.Stdout
-		c.Stderr = os.Stderr
-		if err = c.Run(); err != nil {
-			return fmt.Errorf("upgrade failed: %w", err)
+		if legacyUpgrade {
+			return legacyShellUpgrade(latest)
 		}
 
-		return nil
+		return selfUpdate(ctx, src, latest)
 	},
 }
 
@@ -73,44 +191,461 @@ type tagInfo struct {
 	Name string `json:"name"`
 }
 
-// getLatestTag retrieves the list of tags from GitHub and returns the latest valid version tag.
-func getLatestTag() (string, error) {
-	resp, err := http.Get(tagsURL)
+// releaseAsset is a single downloadable asset attached to a GitHub release.
+type releaseAsset struct {
+	Name               string `json:"name"`
+	BrowserDownloadURL string `json:"browser_download_url"`
+}
+
+// releaseInfo is a minimal structure for GitHub release API responses.
+type releaseInfo struct {
+	TagName string         `json:"tag_name"`
+	Assets  []releaseAsset `json:"assets"`
+}
+
+// legacyShellUpgrade performs the upgrade by downloading and running get.sh under sudo,
+// the way `containerlab version upgrade` worked before native self-update was added. It
+// is kept behind the --legacy flag for hosts where the self-update path doesn't apply.
+// get.sh is always fetched from GitHub, so --legacy is rejected for non-github release
+// sources rather than silently ignoring --release-source/--release-base-url.
+func legacyShellUpgrade(latest string) error {
+	// Create a temporary file to hold the upgrade script.
+	f, err := os.CreateTemp("", "containerlab")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	// Download the upgrade script into the temp file.
+	if err = downloadFile(downloadURL, f); err != nil {
+		return fmt.Errorf("failed to download upgrade script: %w", err)
+	}
+
+	// Ensure the file is executable.
+	if err = f.Chmod(0755); err != nil {
+		return fmt.Errorf("failed to set script as executable: %w", err)
+	}
+
+	// Prepare and run the upgrade script.
+	// The environment variable CLAB_VERSION is passed so that the script installs the latest version.
+	c := exec.Command("sudo", "bash", f.Name())
+	c.Env = append(os.Environ(), "CLAB_VERSION="+latest)
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err = c.Run(); err != nil {
+		return fmt.Errorf("upgrade failed: %w", err)
+	}
+
+	return nil
+}
+
+// selfUpdate fetches the release installer for tag from src, verifies it (to whatever
+// extent src supports) and atomically replaces the running binary with its contents.
+func selfUpdate(ctx context.Context, src ReleaseSource, tag string) error {
+	rc, err := src.FetchInstaller(ctx, tag)
+	if err != nil {
+		return fmt.Errorf("failed to fetch release installer for %s: %w", tag, err)
+	}
+	defer rc.Close()
+
+	return installFromReader(rc)
+}
+
+// selfUpdateFromFile installs containerlab from a local release tarball, for air-gapped
+// hosts that cannot reach any release source. No checksum is verified in this mode
+// since the operator is expected to have vetted the file out-of-band.
+func selfUpdateFromFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	return installFromReader(f)
+}
+
+// installFromReader extracts the containerlab binary out of the tarball read from r and
+// atomically swaps it in for the currently running binary.
+func installFromReader(r io.Reader) error {
+	tmpDir, err := os.MkdirTemp("", "containerlab-install")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	newBinPath, err := extractBinary(r, tmpDir)
+	if err != nil {
+		return fmt.Errorf("failed to extract containerlab binary: %w", err)
+	}
+
+	return swapBinary(newBinPath)
+}
+
+// releaseAssetName returns the expected release tarball name for tag on the running
+// OS/arch, e.g. containerlab_0.54.1_linux_amd64.tar.gz.
+func releaseAssetName(tag string) (string, error) {
+	if runtime.GOOS != "linux" {
+		return "", fmt.Errorf("self-update is only supported on linux, got %s", runtime.GOOS)
+	}
+
+	arch, err := releaseArch()
 	if err != nil {
 		return "", err
 	}
+
+	v := strings.TrimPrefix(tag, "v")
+
+	return fmt.Sprintf("containerlab_%s_linux_%s.tar.gz", v, arch), nil
+}
+
+// releaseArch maps runtime.GOARCH onto the arch suffix used in release asset names.
+func releaseArch() (string, error) {
+	switch runtime.GOARCH {
+	case "amd64":
+		return "amd64", nil
+	case "arm64":
+		return "arm64", nil
+	case "arm":
+		return "armv7", nil
+	default:
+		return "", fmt.Errorf("unsupported architecture %s", runtime.GOARCH)
+	}
+}
+
+// findAsset returns the asset named name, or nil if it isn't part of the release.
+func findAsset(assets []releaseAsset, name string) *releaseAsset {
+	for i := range assets {
+		if assets[i].Name == name {
+			return &assets[i]
+		}
+	}
+
+	return nil
+}
+
+// verifyChecksum downloads checksumsURL (a checksums.txt-style file) and verifies that
+// the sha256 of the file at path matches the entry for assetName.
+func verifyChecksum(ctx context.Context, path, assetName, checksumsURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checksumsURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build checksums request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download checksums: %w", err)
+	}
 	defer resp.Body.Close()
 
-	var tags []tagInfo
-	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read checksums: %w", err)
+	}
+
+	var want string
+	for _, line := range strings.Split(string(body), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[1] == assetName {
+			want = fields[0]
+			break
+		}
+	}
+	if want == "" {
+		return fmt.Errorf("no checksum entry for %s in checksums.txt", assetName)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return err
+	}
+
+	got := hex.EncodeToString(h.Sum(nil))
+	if !strings.EqualFold(got, want) {
+		return fmt.Errorf("checksum mismatch for %s: want %s, got %s", assetName, want, got)
+	}
+
+	return nil
+}
+
+// minisignAlgEd25519 is the sig_alg/kdf_alg tag minisign uses for a plain (non-prehashed)
+// ed25519 signature, i.e. one taken directly over the signed file rather than over its
+// BLAKE2b hash.
+var minisignAlgEd25519 = [2]byte{'E', 'd'}
+
+// minisignPublicKey is a parsed minisign public key: the key ID lets a signature be
+// matched to the key that's supposed to have produced it, independently of whether the
+// ed25519.Verify itself succeeds.
+type minisignPublicKey struct {
+	keyID [8]byte
+	key   ed25519.PublicKey
+}
+
+// parseMinisignPublicKey decodes a base64 minisign public key blob, as printed by
+// `minisign -G` or found in a `minisign.pub` file (without its "untrusted comment:"
+// header line).
+func parseMinisignPublicKey(b64 string) (minisignPublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(b64))
+	if err != nil {
+		return minisignPublicKey{}, fmt.Errorf("invalid minisign public key encoding: %w", err)
+	}
+	// 2-byte algorithm tag + 8-byte key ID + 32-byte ed25519 public key.
+	if len(raw) != 2+8+ed25519.PublicKeySize {
+		return minisignPublicKey{}, fmt.Errorf("invalid minisign public key length %d", len(raw))
+	}
+	if [2]byte{raw[0], raw[1]} != minisignAlgEd25519 {
+		return minisignPublicKey{}, fmt.Errorf("unsupported minisign public key algorithm %q", raw[:2])
+	}
+
+	var pk minisignPublicKey
+	copy(pk.keyID[:], raw[2:10])
+	pk.key = ed25519.PublicKey(raw[10:])
+
+	return pk, nil
+}
+
+// minisignSignature is a parsed minisign .sig file: the per-file signature plus the
+// trusted comment and global signature minisign uses to additionally authenticate that
+// comment (and implicitly, that the per-file signature wasn't swapped for another one
+// signed by the same key).
+type minisignSignature struct {
+	keyID          [8]byte
+	sig            []byte
+	trustedComment string
+	globalSig      []byte
+}
+
+// parseMinisignSignature parses a minisign .sig file body, e.g.:
+//
+//	untrusted comment: signature from minisign secret key
+//	RUQyJvi/9...
+//	trusted comment: timestamp:1700000000	file:containerlab_linux_amd64.tar.gz
+//	+f2q...
+func parseMinisignSignature(body []byte) (minisignSignature, error) {
+	lines := strings.Split(strings.ReplaceAll(string(body), "\r\n", "\n"), "\n")
+	if len(lines) < 4 {
+		return minisignSignature{}, fmt.Errorf("malformed minisign signature: expected at least 4 lines, got %d", len(lines))
+	}
+
+	sigBlob, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[1]))
+	if err != nil {
+		return minisignSignature{}, fmt.Errorf("invalid minisign signature encoding: %w", err)
+	}
+	// 2-byte algorithm tag + 8-byte key ID + 64-byte ed25519 signature.
+	if len(sigBlob) != 2+8+ed25519.SignatureSize {
+		return minisignSignature{}, fmt.Errorf("invalid minisign signature length %d", len(sigBlob))
+	}
+	if [2]byte{sigBlob[0], sigBlob[1]} != minisignAlgEd25519 {
+		return minisignSignature{}, fmt.Errorf("unsupported minisign signature algorithm %q "+
+			"(prehashed minisign signatures are not supported)", sigBlob[:2])
+	}
+
+	const trustedCommentPrefix = "trusted comment: "
+	if !strings.HasPrefix(lines[2], trustedCommentPrefix) {
+		return minisignSignature{}, fmt.Errorf("malformed minisign signature: missing trusted comment line")
+	}
+
+	globalSig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(lines[3]))
+	if err != nil {
+		return minisignSignature{}, fmt.Errorf("invalid minisign global signature encoding: %w", err)
+	}
+
+	var sig minisignSignature
+	copy(sig.keyID[:], sigBlob[2:10])
+	sig.sig = sigBlob[10:]
+	sig.trustedComment = strings.TrimPrefix(lines[2], trustedCommentPrefix)
+	sig.globalSig = globalSig
+
+	return sig, nil
+}
+
+// verifySignature verifies the minisign detached signature at sigURL over the file at
+// path using the bundled minisign public key. It checks both the per-file signature and
+// the global signature minisign uses to authenticate the trusted comment, and is a
+// no-op (with a warning) when no public key has been compiled in.
+func verifySignature(ctx context.Context, path, sigURL string) error {
+	return verifySignatureWithKey(ctx, path, sigURL, releasePublicKeyB64)
+}
+
+// verifySignatureWithKey is verifySignature with the bundled public key factored out as
+// a parameter, so the minisign verification logic can be exercised directly in tests
+// without reaching for the compiled-in key.
+func verifySignatureWithKey(ctx context.Context, path, sigURL, pubKeyB64 string) error {
+	if pubKeyB64 == "" {
+		fmt.Println("warning: no bundled release public key, skipping signature verification")
+		return nil
+	}
+
+	pubKey, err := parseMinisignPublicKey(pubKeyB64)
+	if err != nil {
+		return fmt.Errorf("invalid bundled release public key: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sigURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to build signature request: %w", err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to download signature: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	sig, err := parseMinisignSignature(body)
+	if err != nil {
+		return err
+	}
+	if sig.keyID != pubKey.keyID {
+		return fmt.Errorf("signature key ID %x does not match bundled public key ID %x", sig.keyID, pubKey.keyID)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if !ed25519.Verify(pubKey.key, data, sig.sig) {
+		return fmt.Errorf("signature verification failed for %s", filepath.Base(path))
+	}
+
+	// minisign's global signature covers the 64-byte per-file signature followed by the
+	// trusted comment.
+	globalMsg := append(append([]byte{}, sig.sig...), []byte(sig.trustedComment)...)
+	if !ed25519.Verify(pubKey.key, globalMsg, sig.globalSig) {
+		return fmt.Errorf("global signature verification failed for %s (trusted comment may have been tampered with)",
+			filepath.Base(path))
+	}
+
+	return nil
+}
+
+// extractBinary extracts the containerlab entry out of the tar.gz stream r into destDir
+// and returns the path of the extracted file.
+func extractBinary(r io.Reader, destDir string) (string, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
 		return "", err
 	}
+	defer gz.Close()
 
-	var latestVersion *version.Version
-	var latestTag string
-	for _, t := range tags {
-		// Filter out tags that do not start with "v" immediately followed by a digit.
-		if !strings.HasPrefix(t.Name, "v") || len(t.Name) < 2 || (t.Name[1] < '0' || t.Name[1] > '9') {
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if filepath.Base(hdr.Name) != "containerlab" {
 			continue
 		}
 
-		// Remove the "v" prefix for proper semantic version parsing.
-		vStr := strings.TrimPrefix(t.Name, "v")
-		v, err := version.NewVersion(vStr)
+		outPath := filepath.Join(destDir, "containerlab.new")
+		out, err := os.OpenFile(outPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0755)
 		if err != nil {
-			continue
+			return "", err
+		}
+
+		if _, err := io.Copy(out, tr); err != nil { //nolint:gosec // hdr.Name is checked above.
+			out.Close()
+			return "", err
 		}
-		if latestVersion == nil || v.GreaterThan(latestVersion) {
-			latestVersion = v
-			latestTag = t.Name
+		out.Close()
+
+		return outPath, nil
+	}
+
+	return "", fmt.Errorf("containerlab binary not found in tarball")
+}
+
+// swapBinary atomically replaces the running containerlab binary with newBinPath,
+// keeping the previous binary as containerlab.old next to it. If the new binary fails
+// a smoke test, the previous binary is restored automatically.
+func swapBinary(newBinPath string) error {
+	exePath, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate running binary: %w", err)
+	}
+
+	exePath, err = filepath.EvalSymlinks(exePath)
+	if err != nil {
+		return fmt.Errorf("failed to resolve running binary path: %w", err)
+	}
+
+	dir := filepath.Dir(exePath)
+	oldPath := filepath.Join(dir, "containerlab.old")
+	stagedPath := filepath.Join(dir, ".containerlab.new")
+
+	if err := copyFile(newBinPath, stagedPath, 0755); err != nil {
+		return fmt.Errorf("failed to stage new binary in %s: %w", dir, err)
+	}
+	defer os.Remove(stagedPath)
+
+	// Remove any leftover backup from a previous upgrade so the rename below can't fail.
+	_ = os.Remove(oldPath)
+
+	if err := os.Rename(exePath, oldPath); err != nil {
+		return fmt.Errorf("failed to back up running binary: %w", err)
+	}
+
+	if err := os.Rename(stagedPath, exePath); err != nil {
+		_ = os.Rename(oldPath, exePath)
+		return fmt.Errorf("failed to install new binary, rolled back to previous version: %w", err)
+	}
+
+	if err := smokeTest(exePath); err != nil {
+		_ = os.Remove(exePath)
+		if rerr := os.Rename(oldPath, exePath); rerr != nil {
+			return fmt.Errorf("new binary failed smoke test (%v) and rollback failed: %w", err, rerr)
 		}
+		return fmt.Errorf("new binary failed smoke test, rolled back to previous version: %w", err)
+	}
+
+	fmt.Printf("upgrade successful, previous binary kept at %s\n", oldPath)
+
+	return nil
+}
+
+// smokeTest runs `<path> version` to make sure the newly installed binary actually runs.
+func smokeTest(path string) error {
+	out, err := exec.Command(path, "version").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
 	}
 
-	if latestTag == "" {
-		return "", fmt.Errorf("no valid version tag found")
+	return nil
+}
+
+// copyFile copies src to dst, creating dst with the given file mode.
+func copyFile(src, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
 	}
+	defer in.Close()
 
-	return latestTag, nil
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, in)
+
+	return err
 }
 
 // downloadFile downloads a file from the specified URL and writes its contents to the provided file.
@@ -136,6 +671,48 @@ func downloadFile(url string, file *os.File) error {
 	return nil
 }
 
+// downloadToPath downloads url directly into a file at path.
+func downloadToPath(ctx context.Context, url, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s downloading %s", resp.Status, url)
+	}
+
+	out, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	_, err = io.Copy(out, resp.Body)
+
+	return err
+}
+
 func init() {
 	VersionCmd.AddCommand(upgradeCmd)
+	upgradeCmd.Flags().BoolVar(&legacyUpgrade, "legacy", false,
+		"use the legacy get.sh shell script instead of the native self-update")
+	upgradeCmd.Flags().StringVar(&fromFile, "from-file", "",
+		"install containerlab from a local release tarball instead of downloading one (for air-gapped hosts)")
+
+	upgradeCmd.Flags().StringVar(&releaseChannel, "channel", channelStable,
+		"release channel to resolve the latest version from: stable|prerelease "+
+			"(falls back to CLAB_RELEASE_CHANNEL, then release_channel in the release config file)")
+	upgradeCmd.Flags().StringVar(&releaseFilter, "filter", "",
+		"regex matched against tag names before picking the latest one; overrides --channel "+
+			"(falls back to CLAB_RELEASE_FILTER, then release_filter in the release config file)")
+	upgradeCmd.Flags().StringVar(&releasePin, "pin", "",
+		"pin the upgrade to an exact version tag, skipping channel/filter resolution entirely "+
+			"(falls back to CLAB_RELEASE_PIN, then release_pin in the release config file)")
 }
@@ -0,0 +1,117 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package version
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// resetUpgradeFlags restores upgradeCmd's --channel/--filter/--pin flags (and the
+// package vars they're bound to) to their just-registered, not-yet-passed state, so
+// each subtest starts from a clean slate regardless of what earlier subtests set.
+func resetUpgradeFlags(t *testing.T) {
+	t.Helper()
+
+	for _, name := range []string{"channel", "filter", "pin"} {
+		f := upgradeCmd.Flags().Lookup(name)
+		if err := f.Value.Set(f.DefValue); err != nil {
+			t.Fatalf("failed to reset --%s: %v", name, err)
+		}
+		f.Changed = false
+	}
+}
+
+// writeReleaseConfig points XDG_CONFIG_HOME at a fresh temp dir and writes cfg as the
+// release config file underneath it, for the duration of the test.
+func writeReleaseConfig(t *testing.T, cfg string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	if cfg == "" {
+		return
+	}
+
+	cfgDir := filepath.Join(dir, "containerlab")
+	if err := os.MkdirAll(cfgDir, 0o755); err != nil {
+		t.Fatalf("failed to create config dir: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cfgDir, "release.json"), []byte(cfg), 0o644); err != nil {
+		t.Fatalf("failed to write release config: %v", err)
+	}
+}
+
+func TestResolvePolicyChannelPrecedence(t *testing.T) {
+	tests := map[string]struct {
+		setFlag bool
+		envVal  string
+		cfgJSON string
+		want    string
+	}{
+		"flag wins over env and config": {
+			setFlag: true,
+			envVal:  "prerelease",
+			cfgJSON: `{"release_channel":"prerelease"}`,
+			want:    channelStable, // the flag is explicitly set to its default, "stable"
+		},
+		"env wins over config when flag unset": {
+			envVal:  "prerelease",
+			cfgJSON: `{"release_channel":"stable"}`,
+			want:    "prerelease",
+		},
+		"config used when flag and env unset": {
+			cfgJSON: `{"release_channel":"prerelease"}`,
+			want:    "prerelease",
+		},
+		"default used when nothing set": {
+			want: channelStable,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			resetUpgradeFlags(t)
+			writeReleaseConfig(t, tc.cfgJSON)
+
+			if tc.envVal != "" {
+				t.Setenv("CLAB_RELEASE_CHANNEL", tc.envVal)
+			}
+			if tc.setFlag {
+				if err := upgradeCmd.Flags().Set("channel", channelStable); err != nil {
+					t.Fatalf("failed to set --channel: %v", err)
+				}
+			}
+
+			policy, err := resolvePolicy(upgradeCmd)
+			if err != nil {
+				t.Fatalf("resolvePolicy() error = %v", err)
+			}
+			if policy.channel != tc.want {
+				t.Errorf("resolvePolicy().channel = %q, want %q", policy.channel, tc.want)
+			}
+		})
+	}
+}
+
+func TestResolvePolicyPinPrecedence(t *testing.T) {
+	resetUpgradeFlags(t)
+	writeReleaseConfig(t, `{"release_pin":"v0.50.0"}`)
+	t.Setenv("CLAB_RELEASE_PIN", "v0.51.0")
+
+	if err := upgradeCmd.Flags().Set("pin", "v0.52.0"); err != nil {
+		t.Fatalf("failed to set --pin: %v", err)
+	}
+
+	policy, err := resolvePolicy(upgradeCmd)
+	if err != nil {
+		t.Fatalf("resolvePolicy() error = %v", err)
+	}
+	if policy.pin != "v0.52.0" {
+		t.Errorf("resolvePolicy().pin = %q, want the explicitly-passed flag value v0.52.0", policy.pin)
+	}
+}
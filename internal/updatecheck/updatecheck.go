@@ -0,0 +1,209 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package updatecheck implements a small, cached check for newer containerlab
+// releases. It is deliberately independent of any CLI framework so it can be
+// unit tested and reused by anything that wants to know "is there a newer
+// release than the one I'm running", without itself deciding when or whether
+// to run.
+package updatecheck
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+)
+
+const (
+	tagsURL = "https://api.github.com/repos/srl-labs/containerlab/tags"
+
+	// Interval is the minimum amount of time between two GitHub lookups for
+	// the same running version.
+	Interval = 24 * time.Hour
+
+	configDirName = "containerlab"
+	cacheFileName = "last_update_check.json"
+)
+
+// prereleaseMarkers mirrors the "stable" channel definition used by
+// `version upgrade` (see tagPolicy in cmd/version/upgrade.go): the background
+// check must never recommend a tag that `version upgrade` wouldn't itself
+// resolve to by default, or the notice and the upgrade it points at disagree.
+var prereleaseMarkers = []string{"-rc", "-beta", "-alpha", "-dev"}
+
+// isStableTag reports whether tag is a release under the default "stable"
+// channel, i.e. not a prerelease.
+func isStableTag(tag string) bool {
+	for _, marker := range prereleaseMarkers {
+		if strings.Contains(tag, marker) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Entry is the on-disk representation of the result of the last update check.
+type Entry struct {
+	LatestTag  string    `json:"tag"`
+	CheckedAt  time.Time `json:"checked_at"`
+	CurrentTag string    `json:"current_tag"`
+}
+
+// CachePath returns the path to the cached update-check result,
+// ~/.config/containerlab/last_update_check.json.
+func CachePath() (string, error) {
+	cfgDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cfgDir, configDirName, cacheFileName), nil
+}
+
+// ReadCache reads the cached update-check result. It returns a nil entry (and
+// no error) when no cache file has been written yet.
+func ReadCache() (*Entry, error) {
+	path, err := CachePath()
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var e Entry
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+
+	return &e, nil
+}
+
+func writeCache(e *Entry) error {
+	path, err := CachePath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o644)
+}
+
+// Check returns the cached latest-tag entry for currentTag, refreshing it from
+// GitHub first if the cache is missing, stale (older than Interval) or was
+// produced for a different running version. ctx bounds the GitHub API call.
+func Check(ctx context.Context, currentTag string) (*Entry, error) {
+	if cached, err := ReadCache(); err == nil && cached != nil &&
+		cached.CurrentTag == currentTag && time.Since(cached.CheckedAt) < Interval {
+		return cached, nil
+	}
+
+	latest, err := fetchLatestTag(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &Entry{
+		LatestTag:  latest,
+		CheckedAt:  time.Now(),
+		CurrentTag: currentTag,
+	}
+
+	if err := writeCache(entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+type tagInfo struct {
+	Name string `json:"name"`
+}
+
+// fetchLatestTag queries the GitHub tags API and returns the highest stable semver tag
+// found, skipping names that aren't valid "vX.Y.Z" tags and prereleases (-rc/-beta/
+// -alpha/-dev), the same default channel `version upgrade` resolves to.
+func fetchLatestTag(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, tagsURL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var tags []tagInfo
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return "", err
+	}
+
+	var latestVersion *version.Version
+	var latestTag string
+	for _, t := range tags {
+		if !strings.HasPrefix(t.Name, "v") || len(t.Name) < 2 || t.Name[1] < '0' || t.Name[1] > '9' {
+			continue
+		}
+
+		if !isStableTag(t.Name) {
+			continue
+		}
+
+		v, err := version.NewVersion(strings.TrimPrefix(t.Name, "v"))
+		if err != nil {
+			continue
+		}
+
+		if latestVersion == nil || v.GreaterThan(latestVersion) {
+			latestVersion = v
+			latestTag = t.Name
+		}
+	}
+
+	if latestTag == "" {
+		return "", fmt.Errorf("no valid version tag found")
+	}
+
+	return latestTag, nil
+}
+
+// IsNewer reports whether latestTag is a greater semantic version than
+// currentTag. Tags that fail to parse are treated as "not newer".
+func IsNewer(latestTag, currentTag string) bool {
+	latest, err := version.NewVersion(strings.TrimPrefix(latestTag, "v"))
+	if err != nil {
+		return false
+	}
+
+	current, err := version.NewVersion(strings.TrimPrefix(currentTag, "v"))
+	if err != nil {
+		return false
+	}
+
+	return latest.GreaterThan(current)
+}